@@ -2,18 +2,57 @@ package darner
 
 import (
 	"math"
+	"math/rand"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// Jitter selects how randomness is mixed into a Backoff's sleep duration,
+// to avoid many readers reconnecting in lockstep after a shared Darner
+// outage. The algorithms follow the AWS "Exponential Backoff And Jitter"
+// post.
+type Jitter int
+
+const (
+	// JitterNone returns the deterministic exponential duration from
+	// ForAttempt, unmodified. This is the default, matching prior
+	// behavior.
+	JitterNone Jitter = iota
+	// JitterFull returns a uniformly random duration between 0 and the
+	// deterministic duration.
+	JitterFull
+	// JitterEqual returns half of the deterministic duration plus a
+	// uniformly random amount up to the other half.
+	JitterEqual
+	// JitterDecorrelated ignores the deterministic exponential curve
+	// entirely and instead tracks the previous sleep, returning a
+	// uniformly random duration between Min and three times the
+	// previous sleep, capped at Max.
+	JitterDecorrelated
+)
+
 type Backoff struct {
 	attempt, Factor float64
-	Min, Max time.Duration
+	Min, Max        time.Duration
+	Jitter          Jitter
+
+	mu   sync.Mutex
+	rnd  *rand.Rand
+	prev time.Duration
 }
 
 func (b *Backoff) Duration() time.Duration {
-	d := b.ForAttempt(b.attempt)
+	if b.Jitter == JitterDecorrelated {
+		return b.decorrelated()
+	}
+
+	b.mu.Lock()
+	attempt := b.attempt
 	b.attempt++
-	return d
+	b.mu.Unlock()
+
+	return b.applyJitter(b.ForAttempt(attempt))
 }
 
 const maxInt64 = float64(math.MaxInt64 - 512)
@@ -51,6 +90,109 @@ func (b *Backoff) ForAttempt(attempt float64) time.Duration {
 	return dur
 }
 
+// applyJitter mixes randomness into a deterministic duration produced by
+// ForAttempt, per the Jitter strategy selected. JitterDecorrelated is
+// handled separately by decorrelated, since it doesn't use ForAttempt at
+// all.
+func (b *Backoff) applyJitter(d time.Duration) time.Duration {
+	switch b.Jitter {
+	case JitterFull:
+		if d <= 0 {
+			return 0
+		}
+		return time.Duration(b.int63n(int64(d)))
+	case JitterEqual:
+		if d <= 0 {
+			return 0
+		}
+		half := d / 2
+		return half + time.Duration(b.int63n(int64(half)+1))
+	default:
+		return d
+	}
+}
+
+func (b *Backoff) decorrelated() time.Duration {
+	min := b.Min
+	if min <= 0 {
+		min = 100 * time.Millisecond
+	}
+	max := b.Max
+	if max <= 0 {
+		max = 10 * time.Second
+	}
+
+	b.mu.Lock()
+	prev := b.prev
+	if prev <= 0 {
+		prev = min
+	}
+	b.mu.Unlock()
+
+	span := int64(prev)*3 - int64(min)
+	d := min
+	if span > 0 {
+		d = min + time.Duration(b.int63n(span))
+	}
+	if d > max {
+		d = max
+	}
+
+	b.mu.Lock()
+	b.prev = d
+	b.attempt++
+	b.mu.Unlock()
+
+	return d
+}
+
+// seedCounter is mixed into every lazily-seeded *rand.Rand so that clones
+// created within the same nanosecond - exactly what happens when a batch
+// of ClusterReader goroutines all hit their first failure together during
+// an outage - don't end up with identical seeds and therefore correlated
+// jitter.
+var seedCounter uint64
+
+func nextSeed() int64 {
+	n := atomic.AddUint64(&seedCounter, 1)
+	return time.Now().UnixNano() ^ int64(n*0x9E3779B97F4A7C15)
+}
+
+// int63n returns a random int64 in [0, n) from this Backoff's own
+// *rand.Rand, lazily seeded on first use and guarded by a mutex so a
+// single Backoff can be shared safely across goroutines. Prefer Clone so
+// each goroutine holds independent state instead of contending on this
+// lock.
+func (b *Backoff) int63n(n int64) int64 {
+	if n <= 0 {
+		return 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.rnd == nil {
+		b.rnd = rand.New(rand.NewSource(nextSeed()))
+	}
+	return b.rnd.Int63n(n)
+}
+
 func (b *Backoff) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
 	b.attempt = 0
+	b.prev = 0
+}
+
+// Clone returns a new Backoff with the same configuration (Min, Max,
+// Factor, Jitter) but fresh attempt/jitter state, so that each
+// ClusterReader goroutine can hold its own Backoff instead of racing on a
+// shared one.
+func (b *Backoff) Clone() *Backoff {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return &Backoff{
+		Factor: b.Factor,
+		Min:    b.Min,
+		Max:    b.Max,
+		Jitter: b.Jitter,
+	}
 }
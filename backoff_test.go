@@ -0,0 +1,61 @@
+package darner
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoffDecorrelatedStaysWithinBounds(t *testing.T) {
+	b := &Backoff{
+		Min:    50 * time.Millisecond,
+		Max:    time.Second,
+		Factor: 2,
+		Jitter: JitterDecorrelated,
+	}
+
+	for i := 0; i < 1000; i++ {
+		d := b.Duration()
+		if d < b.Min || d > b.Max {
+			t.Fatalf("attempt %d: decorrelated duration %s outside [%s, %s]", i, d, b.Min, b.Max)
+		}
+	}
+}
+
+func TestBackoffDecorrelatedMinEqualsMaxReturnsMin(t *testing.T) {
+	// With Min == Max, the first call draws from [Min, 3*Min) and the
+	// result is then capped at Max, so it must come out as exactly Min.
+	b := &Backoff{
+		Min:    time.Second,
+		Max:    time.Second,
+		Factor: 2,
+		Jitter: JitterDecorrelated,
+	}
+
+	d := b.Duration()
+	if d != b.Min {
+		t.Fatalf("got %s, want Min %s", d, b.Min)
+	}
+}
+
+func TestBackoffCloneDecorrelatedSequencesAreIndependent(t *testing.T) {
+	base := &Backoff{
+		Min:    10 * time.Millisecond,
+		Max:    5 * time.Second,
+		Factor: 2,
+		Jitter: JitterDecorrelated,
+	}
+
+	a := base.Clone()
+	b := base.Clone()
+
+	same := true
+	for i := 0; i < 50; i++ {
+		if a.Duration() != b.Duration() {
+			same = false
+			break
+		}
+	}
+	if same {
+		t.Fatal("two clones produced identical decorrelated sequences; expected independent rand state")
+	}
+}
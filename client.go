@@ -1,47 +1,142 @@
 package darner
 
 import (
+	"context"
 	"fmt"
 	"github.com/apuckey/darner-queue-go/memcache"
 	"net"
+	"sync"
 	"time"
 )
 
+// maxPooledConns caps how many idle *Context-path connections a Client
+// keeps around per queue for reuse, so a long-polling ClusterReader
+// doesn't pay a full TCP connect+teardown on every poll cycle.
+const maxPooledConns = 4
+
 type Client struct {
 	Timeout time.Duration
 	client  *memcache.Client
+	addr    string
+
+	poolMu sync.Mutex
+	pool   map[string][]*memcache.PinnedConn
+}
+
+// Addr returns the host:port this Client was constructed with, for use as
+// a metrics or logging label.
+func (c *Client) Addr() string {
+	return c.addr
+}
+
+// QueueItem is a single message read back from a Darner queue. When it was
+// read with reliable delivery enabled, Ack or Nack must be called exactly
+// once to resolve the transaction Darner opened for it; for plain reads
+// both are no-ops.
+type QueueItem struct {
+	Message string
+
+	reliable bool
+	queue    string
+	conn     *memcache.PinnedConn
+	afterAck func()
+}
+
+// Ack commits a reliably-read item, telling Darner it was processed
+// successfully. It is a no-op for items that were not read reliably.
+func (i *QueueItem) Ack() error {
+	if !i.reliable {
+		return nil
+	}
+	defer i.release()
+	return i.conn.Close(i.queue)
+}
+
+// Nack aborts a reliably-read item's transaction, returning it to the head
+// of the queue for redelivery. It is a no-op for items that were not read
+// reliably.
+func (i *QueueItem) Nack() error {
+	if !i.reliable {
+		return nil
+	}
+	defer i.release()
+	return i.conn.Abort(i.queue)
+}
+
+func (i *QueueItem) release() {
+	i.conn.Release()
+	if i.afterAck != nil {
+		i.afterAck()
+	}
 }
 
 func NewClient(host string, port, timeout int) *Client {
-	mc := memcache.New(fmt.Sprintf("%s:%d", host, port))
+	addr := fmt.Sprintf("%s:%d", host, port)
+	mc := memcache.New(addr)
 	mc.Timeout = time.Duration(timeout) * time.Second * 2
 
 	return &Client{
-		client: mc,
+		client:  mc,
+		addr:    addr,
+		pool:    make(map[string][]*memcache.PinnedConn),
 		Timeout: time.Duration(timeout) * time.Second,
 	}
 }
 
-func (c *Client) Get(queueName string, maxItems int32, autoAbort time.Duration) (*QueueItem, error) {
-	item, err := c.client.Get(fmt.Sprintf("%s/t=%d", queueName, int32(c.Timeout/time.Millisecond)))
-	if item != nil {
-		return &QueueItem{
-			Message: string(item.Value),
-		}, err
+// getPooledConn returns an idle connection previously returned by
+// putPooledConn for queueName, or dials a fresh one if none is available.
+func (c *Client) getPooledConn(queueName string) (*memcache.PinnedConn, error) {
+	c.poolMu.Lock()
+	if conns := c.pool[queueName]; len(conns) > 0 {
+		conn := conns[len(conns)-1]
+		c.pool[queueName] = conns[:len(conns)-1]
+		c.poolMu.Unlock()
+		return conn, nil
+	}
+	c.poolMu.Unlock()
+
+	addr, err := c.client.PickServer(queueName)
+	if err != nil {
+		return nil, err
 	}
-	return nil, err
+	return c.client.DialPinned(addr, c.Timeout)
 }
 
-func (c *Client) Set(queueName, message string) (err error) {
-	item := &memcache.Item{
-		Key: queueName,
-		Value: []byte(message),
+// putPooledConn returns a connection to the pool for reuse. Callers must
+// only do this after a command completed cleanly on it (including a cache
+// miss) - a connection that errored mid-protocol, or whose deadline was
+// forced into the past by a cancelled context, may be holding a partial
+// response and must be discarded instead.
+func (c *Client) putPooledConn(queueName string, conn *memcache.PinnedConn) {
+	c.poolMu.Lock()
+	defer c.poolMu.Unlock()
+	if len(c.pool[queueName]) >= maxPooledConns {
+		conn.Release()
+		return
 	}
-	err = c.client.Set(item)
-	return
+	c.pool[queueName] = append(c.pool[queueName], conn)
+}
+
+func (c *Client) Get(queueName string, maxItems int32, autoAbort time.Duration) (*QueueItem, error) {
+	return c.GetContext(context.Background(), queueName, maxItems, autoAbort)
+}
+
+// GetReliable behaves like Get, but opens a Darner transaction for the
+// item it returns: the item is not removed from the queue until the
+// caller calls Ack, and is returned to the queue if the caller calls Nack
+// or the connection is lost before either is called. Each call to
+// GetReliable pins a dedicated connection to the item until it is
+// resolved, so callers that enable this mode should bound the number of
+// unacked items they allow in flight (see ClusterReader.MaxInFlight).
+func (c *Client) GetReliable(queueName string, autoAbort time.Duration) (*QueueItem, error) {
+	return c.GetReliableContext(context.Background(), queueName, autoAbort)
+}
+
+func (c *Client) Set(queueName, message string) (err error) {
+	return c.SetContext(context.Background(), queueName, message)
 }
 
 func (c *Client) Stats() (servers map[net.Addr]*memcache.ServerStats, err error) {
 	servers, err = c.client.StatsServers()
 	return
-}
\ No newline at end of file
+}
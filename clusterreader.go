@@ -1,10 +1,9 @@
 package darner
 
 import (
+	"context"
 	"errors"
-	"fmt"
 	"github.com/apuckey/darner-queue-go/memcache"
-	"github.com/apuckey/scribe-logger-go"
 	"sync"
 	"time"
 )
@@ -14,12 +13,57 @@ type ClusterReader struct {
 	AbortTimeout time.Duration
 	Backoff      *Backoff
 
+	// ReliableRead switches the reader to Darner's transactional GET: items
+	// are only removed from the queue once the consumer calls
+	// item.Ack(), and are returned to the queue by item.Nack() or by the
+	// connection being lost before either is called.
+	ReliableRead bool
+	// MaxInFlight caps, per client, how many reliably-read items may be
+	// unacked at once. It is ignored unless ReliableRead is set. 0 (the
+	// default) means unlimited.
+	MaxInFlight int
+
+	// Logger receives this reader's error/info/debug messages. If nil,
+	// the package-level default set by SetLogger is used (NopLogger by
+	// default).
+	Logger Logger
+
 	clients []*Client
 
-	closed chan struct{}
+	eventMu sync.Mutex
+	onEvent func(Event)
+
+	cancel context.CancelFunc
 	wg     sync.WaitGroup
 }
 
+// SetOnEvent sets the hook called for every message read, cache miss,
+// error, and backoff sleep the reader observes, replacing any hook set
+// before. The hook must not block. Safe to call concurrently with a
+// running reader.
+func (r *ClusterReader) SetOnEvent(hook func(Event)) {
+	r.eventMu.Lock()
+	r.onEvent = hook
+	r.eventMu.Unlock()
+}
+
+// AddOnEvent adds hook to the chain of hooks called for every event this
+// reader observes, without discarding any hook already set. Safe to call
+// concurrently with a running reader, which is what lets
+// darner/metrics.Collector.Observe wire itself up regardless of whether
+// the reader has already been started.
+func (r *ClusterReader) AddOnEvent(hook func(Event)) {
+	r.eventMu.Lock()
+	defer r.eventMu.Unlock()
+	prev := r.onEvent
+	r.onEvent = func(e Event) {
+		if prev != nil {
+			prev(e)
+		}
+		hook(e)
+	}
+}
+
 func NewClusterReader(clients []*Client) *ClusterReader {
 	return &ClusterReader{
 		clients: clients,
@@ -37,51 +81,140 @@ func NewClusterReader(clients []*Client) *ClusterReader {
 }
 
 func (r *ClusterReader) ReadIntoChannel(queueName string, ch chan<- *QueueItem) {
-	r.closed = make(chan struct{})
+	r.ReadIntoChannelContext(context.Background(), queueName, ch)
+}
+
+// ReadIntoChannelContext behaves like ReadIntoChannel, but stops all of its
+// reader goroutines as soon as ctx is done, in addition to Close. It
+// replaces the previous ad-hoc "closed" channel with ctx as the single
+// cancellation signal.
+func (r *ClusterReader) ReadIntoChannelContext(ctx context.Context, queueName string, ch chan<- *QueueItem) {
+	ctx, cancel := context.WithCancel(ctx)
+	r.cancel = cancel
 
 	for _, client := range r.clients {
 		r.wg.Add(1)
 
-		go func(client *Client, queueName string, ch chan<- *QueueItem, closed chan struct{}) {
+		var sem chan struct{}
+		if r.ReliableRead && r.MaxInFlight > 0 {
+			sem = make(chan struct{}, r.MaxInFlight)
+		}
+
+		go func(client *Client, queueName string, ch chan<- *QueueItem) {
 			defer r.wg.Done()
 
+			backoff := r.Backoff.Clone()
 			hasFailed := false
 
 			for {
-				item, err := client.Get(queueName, 1, r.AbortTimeout)
+				if sem != nil {
+					select {
+					case sem <- struct{}{}:
+					case <-ctx.Done():
+						return
+					}
+				}
+
+				var item *QueueItem
+				var err error
+				if r.ReliableRead {
+					item, err = client.GetReliableContext(ctx, queueName, r.AbortTimeout)
+				} else {
+					item, err = client.GetContext(ctx, queueName, 1, r.AbortTimeout)
+				}
+
+				if item == nil && sem != nil {
+					<-sem
+				} else if item != nil && sem != nil {
+					item.afterAck = func() { <-sem }
+				}
+
+				// ctx being done is how normal shutdown surfaces from
+				// GetContext/GetReliableContext (as context.Canceled or
+				// context.DeadlineExceeded), not a Darner error - treat it
+				// as a clean stop rather than falling into the error/backoff
+				// path below, which would log a spurious error and leave
+				// EventBackoffStart's gauge stuck non-zero forever.
+				if ctx.Err() != nil {
+					if item != nil {
+						item.Nack()
+					}
+					return
+				}
+
 				if err != nil && !errors.Is(err, memcache.ErrCacheMiss) {
 					// probably decide what to do here. lets just wait for timeout before trying to get new messages for now.
 					// most likely a transient issue ie: restarting darner
 					hasFailed = true
-					logger.Error(fmt.Sprintf("[DarnerQueue]: Error getting message from queue: %s", err.Error()))
-					<-time.After(r.Backoff.Duration())
+					r.emit(Event{Kind: EventError, Server: client.Addr(), Queue: queueName, Err: err})
+					r.logger().Errorf("[DarnerQueue]: Error getting message from queue: %s", err)
+
+					d := backoff.Duration()
+					r.emit(Event{Kind: EventBackoffStart, Server: client.Addr(), Queue: queueName, Duration: d})
+					select {
+					case <-time.After(d):
+						r.emit(Event{Kind: EventBackoffEnd, Server: client.Addr(), Queue: queueName, Duration: d})
+					case <-ctx.Done():
+						return
+					}
 				} else {
 					// normal operation. reset backoff timer
-					r.Backoff.Reset()
+					backoff.Reset()
 					if hasFailed {
-						logger.Info(fmt.Sprintf("[DarnerQueue]: resuming normal operation"))
+						r.logger().Infof("[DarnerQueue]: resuming normal operation")
 						hasFailed = false
 					}
 					if item != nil {
-						ch <- item
+						r.emit(Event{Kind: EventMessageRead, Server: client.Addr(), Queue: queueName})
+						select {
+						case ch <- item:
+						case <-ctx.Done():
+							// Consumer never showed up to drain ch before
+							// shutdown: resolve the item (a no-op unless it
+							// was reliably read, in which case this also
+							// releases its pinned connection and its
+							// MaxInFlight slot) instead of leaking it.
+							if nackErr := item.Nack(); nackErr != nil {
+								r.logger().Errorf("[DarnerQueue]: error nacking in-flight item during shutdown: %s", nackErr)
+							}
+							return
+						}
+					} else {
+						r.emit(Event{Kind: EventCacheMiss, Server: client.Addr(), Queue: queueName})
 					}
 				}
 
 				select {
-				case <-closed:
+				case <-ctx.Done():
 					return
 				default:
 					continue
 				}
 			}
-		}(client, queueName, ch, r.closed)
+		}(client, queueName, ch)
 	}
 	r.wg.Wait()
 }
 
+func (r *ClusterReader) logger() Logger {
+	if r.Logger != nil {
+		return r.Logger
+	}
+	return getDefaultLogger()
+}
+
+func (r *ClusterReader) emit(e Event) {
+	r.eventMu.Lock()
+	hook := r.onEvent
+	r.eventMu.Unlock()
+	if hook != nil {
+		hook(e)
+	}
+}
+
 func (r *ClusterReader) Close() error {
-	if r.closed != nil {
-		close(r.closed)
+	if r.cancel != nil {
+		r.cancel()
 	}
 
 	return nil
@@ -0,0 +1,98 @@
+package darner
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeDarnerServer accepts connections and answers every "get ...\r\n"
+// request with the same item, so a reliable reader always has something
+// to fetch. It never resolves any transaction, so a fetched item stays
+// open (and its MaxInFlight slot held) until the test resolves it or the
+// connection is closed.
+func fakeDarnerServer(t *testing.T) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %s", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if !strings.HasPrefix(line, "get ") {
+						continue
+					}
+					value := "payload"
+					fmt.Fprintf(conn, "VALUE testqueue 0 %d\r\n%s\r\nEND\r\n", len(value), value)
+				}
+			}(conn)
+		}
+	}()
+
+	return ln.Addr().String()
+}
+
+// TestReadIntoChannelContextStopsOnCancelWhenInFlightFull reproduces the
+// MaxInFlight deadlock: with ReliableRead and MaxInFlight=1, once the one
+// slot is held by an unacked item, the reader goroutine parks on the
+// semaphore send at the top of its loop rather than on any of the
+// ctx.Done() cases added elsewhere in this commit. Cancelling ctx must
+// still make ReadIntoChannelContext return.
+func TestReadIntoChannelContextStopsOnCancelWhenInFlightFull(t *testing.T) {
+	addr := fakeDarnerServer(t)
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		t.Fatalf("split host port: %s", err)
+	}
+	var port int
+	if _, err := fmt.Sscanf(portStr, "%d", &port); err != nil {
+		t.Fatalf("parse port: %s", err)
+	}
+
+	client := NewClient(host, port, 1)
+	reader := NewClusterReader([]*Client{client})
+	reader.ReliableRead = true
+	reader.MaxInFlight = 1
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan *QueueItem, 1)
+	done := make(chan struct{})
+	go func() {
+		reader.ReadIntoChannelContext(ctx, "testqueue", ch)
+		close(done)
+	}()
+
+	select {
+	case <-ch:
+		// Slot is now held: the item hasn't been Acked or Nacked.
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the first item")
+	}
+
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("ReadIntoChannelContext did not return after ctx cancellation with MaxInFlight full")
+	}
+}
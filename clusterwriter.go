@@ -1,7 +1,9 @@
 package darner
 
 import (
+	"context"
 	"fmt"
+	"sync"
 	"sync/atomic"
 	"time"
 )
@@ -12,8 +14,36 @@ type ClusterWriter struct {
 	Backoff      *Backoff
 
 	clients []*Client
-	offset uint64
+	offset  uint64
 
+	eventMu sync.Mutex
+	onEvent func(Event)
+}
+
+// SetOnEvent sets the hook called for every message written and every
+// write error, replacing any hook set before. The hook must not block.
+// Safe to call concurrently with a running writer.
+func (w *ClusterWriter) SetOnEvent(hook func(Event)) {
+	w.eventMu.Lock()
+	w.onEvent = hook
+	w.eventMu.Unlock()
+}
+
+// AddOnEvent adds hook to the chain of hooks called for every event this
+// writer observes, without discarding any hook already set. Safe to call
+// concurrently with a running writer, which is what lets
+// darner/metrics.Collector.Observe wire itself up regardless of whether
+// the writer has already been started.
+func (w *ClusterWriter) AddOnEvent(hook func(Event)) {
+	w.eventMu.Lock()
+	defer w.eventMu.Unlock()
+	prev := w.onEvent
+	w.onEvent = func(e Event) {
+		if prev != nil {
+			prev(e)
+		}
+		hook(e)
+	}
 }
 
 func NewClusterWriter(clients []*Client) *ClusterWriter {
@@ -23,15 +53,35 @@ func NewClusterWriter(clients []*Client) *ClusterWriter {
 }
 
 func (w *ClusterWriter) Write(queueName, item string) (err error) {
+	return w.WriteContext(context.Background(), queueName, item)
+}
+
+// WriteContext behaves like Write, but aborts as soon as ctx is done
+// rather than waiting out a slow or wedged connection.
+func (w *ClusterWriter) WriteContext(ctx context.Context, queueName, item string) (err error) {
 	c := w.getClient()
-	if c != nil {
-		err = c.Set(queueName, item)
-	} else {
+	if c == nil {
 		return fmt.Errorf("[DarnerQueue]: Unable to get a client from the pool.")
 	}
+
+	err = c.SetContext(ctx, queueName, item)
+	if err != nil {
+		w.emit(Event{Kind: EventError, Server: c.Addr(), Queue: queueName, Err: err})
+	} else {
+		w.emit(Event{Kind: EventMessageWritten, Server: c.Addr(), Queue: queueName})
+	}
 	return
 }
 
+func (w *ClusterWriter) emit(e Event) {
+	w.eventMu.Lock()
+	hook := w.onEvent
+	w.eventMu.Unlock()
+	if hook != nil {
+		hook(e)
+	}
+}
+
 func (w *ClusterWriter) getClient() *Client {
 	n := len(w.clients)
 	if n == 0 {
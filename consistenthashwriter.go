@@ -0,0 +1,82 @@
+package darner
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ConsistentHashWriter routes each Write to the client responsible for its
+// queue name, using a hash ring with virtual nodes, instead of
+// ClusterWriter's round-robin. This keeps a given queue's messages on one
+// Darner node so a reader doesn't have to fan out across the whole pool
+// to find them.
+type ConsistentHashWriter struct {
+	SetTimeout   time.Duration
+	AbortTimeout time.Duration
+	Backoff      *Backoff
+
+	// OnEvent, if set, is called for every message written and every
+	// write error, same as the hook set by ClusterWriter.SetOnEvent.
+	OnEvent func(Event)
+
+	ring *hashRing
+}
+
+// NewConsistentHashWriter builds a ConsistentHashWriter over clients,
+// with 100 virtual nodes per client on the ring.
+func NewConsistentHashWriter(clients []*Client) *ConsistentHashWriter {
+	w := &ConsistentHashWriter{ring: newHashRing(defaultVirtualNodes)}
+	for _, c := range clients {
+		w.ring.AddClient(c)
+	}
+	return w
+}
+
+// AddClient adds c to the ring, rebalancing which queues route to it.
+// Safe to call while the writer is in use.
+func (w *ConsistentHashWriter) AddClient(c *Client) {
+	w.ring.AddClient(c)
+}
+
+// RemoveClient removes c from the ring, rebalancing the queues that used
+// to route to it across the remaining clients. Safe to call while the
+// writer is in use.
+func (w *ConsistentHashWriter) RemoveClient(c *Client) {
+	w.ring.RemoveClient(c)
+}
+
+// ReaderFor returns a *ClusterReader that polls only the n clients this
+// writer's ring assigns to queueName - the primary plus optional
+// replicas - instead of a reader fleet idly polling every node in the
+// pool, most of which will never hold an item for this queue.
+func (w *ConsistentHashWriter) ReaderFor(queueName string, n int) *ClusterReader {
+	return NewClusterReader(w.ring.GetN(queueName, n))
+}
+
+func (w *ConsistentHashWriter) Write(queueName, item string) error {
+	return w.WriteContext(context.Background(), queueName, item)
+}
+
+// WriteContext behaves like Write, but aborts as soon as ctx is done
+// rather than waiting out a slow or wedged connection.
+func (w *ConsistentHashWriter) WriteContext(ctx context.Context, queueName, item string) error {
+	c := w.ring.Get(queueName)
+	if c == nil {
+		return fmt.Errorf("[DarnerQueue]: Unable to get a client from the pool.")
+	}
+
+	err := c.SetContext(ctx, queueName, item)
+	if err != nil {
+		w.emit(Event{Kind: EventError, Server: c.Addr(), Queue: queueName, Err: err})
+	} else {
+		w.emit(Event{Kind: EventMessageWritten, Server: c.Addr(), Queue: queueName})
+	}
+	return err
+}
+
+func (w *ConsistentHashWriter) emit(e Event) {
+	if w.OnEvent != nil {
+		w.OnEvent(e)
+	}
+}
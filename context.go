@@ -0,0 +1,138 @@
+package darner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/apuckey/darner-queue-go/memcache"
+)
+
+// GetContext behaves like Get, but returns ctx.Err() as soon as ctx is
+// done, even if the underlying Darner GET is still blocked waiting for an
+// item server-side (Darner can hold a GET open for up to Timeout ms). To
+// make that possible it applies ctx's deadline directly to the socket of
+// a connection drawn from the Client's small per-queue pool (see
+// getPooledConn/putPooledConn), rather than reaching into the Client's
+// internal connection management - it's returned to that pool afterward
+// so a long-polling ClusterReader isn't paying a connect+teardown on
+// every poll cycle.
+func (c *Client) GetContext(ctx context.Context, queueName string, maxItems int32, autoAbort time.Duration) (*QueueItem, error) {
+	conn, err := c.getPooledConn(queueName)
+	if err != nil {
+		return nil, err
+	}
+	stop := watchContext(ctx, conn)
+
+	ms := int32(c.Timeout / time.Millisecond)
+	item, err := conn.Get(fmt.Sprintf("%s/t=%d", queueName, ms))
+	stop()
+	if err != nil && !errors.Is(err, memcache.ErrCacheMiss) {
+		conn.Release()
+		return nil, ctxOrErr(ctx, err)
+	}
+	c.putPooledConn(queueName, conn)
+	if err != nil {
+		return nil, err
+	}
+	return &QueueItem{Message: string(item.Value)}, nil
+}
+
+// GetReliableContext behaves like GetReliable, but ctx governs the socket
+// deadline of the connection used for the item in the same way as
+// GetContext. On a clean cache miss the connection never had a
+// transaction opened on it, so it's reusable just like GetContext's and
+// is returned to the same per-queue pool rather than torn down. Once an
+// item has been returned, the connection is pinned to it until Ack/Nack
+// resolves the transaction, and ctx no longer applies.
+func (c *Client) GetReliableContext(ctx context.Context, queueName string, autoAbort time.Duration) (*QueueItem, error) {
+	conn, err := c.getPooledConn(queueName)
+	if err != nil {
+		return nil, err
+	}
+	stop := watchContext(ctx, conn)
+
+	ms := int32(c.Timeout / time.Millisecond)
+	item, err := conn.Get(fmt.Sprintf("%s/t=%d/open", queueName, ms))
+	stop()
+	if item == nil {
+		if errors.Is(err, memcache.ErrCacheMiss) {
+			c.putPooledConn(queueName, conn)
+		} else {
+			conn.Release()
+		}
+		return nil, ctxOrErr(ctx, err)
+	}
+
+	return &QueueItem{
+		Message:  string(item.Value),
+		reliable: true,
+		queue:    queueName,
+		conn:     conn,
+	}, err
+}
+
+// SetContext behaves like Set, but returns ctx.Err() as soon as ctx is
+// done rather than waiting out a slow or wedged connection. Like
+// GetContext, it reuses a pooled connection rather than dialing one per
+// call.
+func (c *Client) SetContext(ctx context.Context, queueName, message string) error {
+	conn, err := c.getPooledConn(queueName)
+	if err != nil {
+		return err
+	}
+	stop := watchContext(ctx, conn)
+
+	err = conn.Set(&memcache.Item{Key: queueName, Value: []byte(message)})
+	stop()
+	if err != nil {
+		conn.Release()
+		return ctxOrErr(ctx, err)
+	}
+	c.putPooledConn(queueName, conn)
+	return nil
+}
+
+func (c *Client) dialFor(queueName string) (*memcache.PinnedConn, error) {
+	addr, err := c.client.PickServer(queueName)
+	if err != nil {
+		return nil, err
+	}
+	return c.client.DialPinned(addr, c.Timeout)
+}
+
+// watchContext applies ctx's deadline to conn up front, and forces conn's
+// deadline into the past as soon as ctx is done so that a blocked read or
+// write returns immediately. The returned stop func must be called once
+// the command has completed; it waits for the watcher goroutine to exit
+// and then clears conn's deadline, so a connection that is pooled or
+// pinned to an item afterward carries no deadline from ctx.
+func watchContext(ctx context.Context, conn *memcache.PinnedConn) (stop func()) {
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		select {
+		case <-ctx.Done():
+			conn.SetDeadline(time.Now())
+		case <-done:
+		}
+	}()
+	return func() {
+		close(done)
+		<-exited
+		conn.SetDeadline(time.Time{})
+	}
+}
+
+func ctxOrErr(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return err
+}
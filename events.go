@@ -0,0 +1,43 @@
+package darner
+
+import "time"
+
+// EventKind identifies what kind of lifecycle event an OnEvent hook
+// received.
+type EventKind int
+
+const (
+	// EventMessageRead is emitted by a ClusterReader each time it
+	// successfully reads a message off a queue.
+	EventMessageRead EventKind = iota
+	// EventMessageWritten is emitted by a ClusterWriter each time it
+	// successfully writes a message to a queue.
+	EventMessageWritten
+	// EventCacheMiss is emitted by a ClusterReader when a GET found
+	// nothing waiting on the queue.
+	EventCacheMiss
+	// EventError is emitted by a ClusterReader or ClusterWriter when an
+	// operation against a server failed for a reason other than a cache
+	// miss.
+	EventError
+	// EventBackoffStart is emitted by a ClusterReader when it begins
+	// waiting out a backoff after EventError. Duration holds how long it
+	// is about to sleep.
+	EventBackoffStart
+	// EventBackoffEnd is emitted once a backoff sleep started by
+	// EventBackoffStart has elapsed without being interrupted. Duration
+	// holds how long was actually waited.
+	EventBackoffEnd
+)
+
+// Event describes a single thing that happened while reading from or
+// writing to a queue. ClusterReader and ClusterWriter report these
+// through their OnEvent hook so that subscribers, such as darner/metrics,
+// can observe queue activity without the core package depending on them.
+type Event struct {
+	Kind     EventKind
+	Server   string
+	Queue    string
+	Err      error
+	Duration time.Duration
+}
@@ -0,0 +1,102 @@
+package darner
+
+import (
+	"fmt"
+	"hash/crc32"
+	"sort"
+	"sync"
+)
+
+const defaultVirtualNodes = 100
+
+// hashRing maps queue names to clients using consistent hashing with
+// virtual nodes, so a given queue is always routed to the same client (or
+// small set of clients) instead of being scattered across the whole pool.
+type hashRing struct {
+	replicas int
+
+	mu      sync.RWMutex
+	points  []uint32
+	owners  map[uint32]*Client
+	clients map[string]*Client // keyed by Client.Addr()
+}
+
+func newHashRing(replicas int) *hashRing {
+	if replicas <= 0 {
+		replicas = defaultVirtualNodes
+	}
+	return &hashRing{
+		replicas: replicas,
+		owners:   make(map[uint32]*Client),
+		clients:  make(map[string]*Client),
+	}
+}
+
+// AddClient inserts c's virtual nodes into the ring, or replaces them if
+// c's address is already present.
+func (h *hashRing) AddClient(c *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.clients[c.Addr()] = c
+	h.rebuildLocked()
+}
+
+// RemoveClient removes c's virtual nodes from the ring.
+func (h *hashRing) RemoveClient(c *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.clients, c.Addr())
+	h.rebuildLocked()
+}
+
+func (h *hashRing) rebuildLocked() {
+	points := make([]uint32, 0, len(h.clients)*h.replicas)
+	owners := make(map[uint32]*Client, len(h.clients)*h.replicas)
+	for addr, c := range h.clients {
+		for i := 0; i < h.replicas; i++ {
+			point := crc32.ChecksumIEEE([]byte(fmt.Sprintf("%s#%d", addr, i)))
+			points = append(points, point)
+			owners[point] = c
+		}
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i] < points[j] })
+	h.points = points
+	h.owners = owners
+}
+
+// Get returns the client responsible for queueName, or nil if the ring
+// has no clients.
+func (h *hashRing) Get(queueName string) *Client {
+	clients := h.GetN(queueName, 1)
+	if len(clients) == 0 {
+		return nil
+	}
+	return clients[0]
+}
+
+// GetN returns up to n distinct clients responsible for queueName, walking
+// the ring clockwise starting at queueName's hash so the first result
+// always matches Get and the rest are its natural replicas.
+func (h *hashRing) GetN(queueName string, n int) []*Client {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if len(h.points) == 0 || n <= 0 {
+		return nil
+	}
+
+	hash := crc32.ChecksumIEEE([]byte(queueName))
+	start := sort.Search(len(h.points), func(i int) bool { return h.points[i] >= hash })
+
+	seen := make(map[string]bool, n)
+	result := make([]*Client, 0, n)
+	for i := 0; i < len(h.points) && len(result) < n; i++ {
+		c := h.owners[h.points[(start+i)%len(h.points)]]
+		if seen[c.Addr()] {
+			continue
+		}
+		seen[c.Addr()] = true
+		result = append(result, c)
+	}
+	return result
+}
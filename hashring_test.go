@@ -0,0 +1,75 @@
+package darner
+
+import "testing"
+
+func newTestClient(addr string) *Client {
+	return &Client{addr: addr}
+}
+
+func TestHashRingGetIsStableForSameQueue(t *testing.T) {
+	ring := newHashRing(defaultVirtualNodes)
+	a := newTestClient("a:1")
+	b := newTestClient("b:1")
+	c := newTestClient("c:1")
+	ring.AddClient(a)
+	ring.AddClient(b)
+	ring.AddClient(c)
+
+	first := ring.Get("my-queue")
+	if first == nil {
+		t.Fatal("expected a client, got nil")
+	}
+	for i := 0; i < 100; i++ {
+		if got := ring.Get("my-queue"); got.Addr() != first.Addr() {
+			t.Fatalf("Get(%q) returned %s on call %d, want stable %s", "my-queue", got.Addr(), i, first.Addr())
+		}
+	}
+}
+
+func TestHashRingGetNReturnsDistinctClients(t *testing.T) {
+	ring := newHashRing(defaultVirtualNodes)
+	clients := []*Client{newTestClient("a:1"), newTestClient("b:1"), newTestClient("c:1")}
+	for _, c := range clients {
+		ring.AddClient(c)
+	}
+
+	got := ring.GetN("my-queue", len(clients))
+	if len(got) != len(clients) {
+		t.Fatalf("GetN returned %d clients, want %d", len(got), len(clients))
+	}
+	seen := make(map[string]bool, len(got))
+	for _, c := range got {
+		if seen[c.Addr()] {
+			t.Fatalf("GetN returned duplicate client %s", c.Addr())
+		}
+		seen[c.Addr()] = true
+	}
+
+	// Asking for more than there are clients should cap at the ring size
+	// instead of repeating or panicking.
+	if got := ring.GetN("my-queue", len(clients)+5); len(got) != len(clients) {
+		t.Fatalf("GetN(n > ring size) returned %d clients, want %d", len(got), len(clients))
+	}
+}
+
+func TestHashRingRemoveClientStopsRoutingToIt(t *testing.T) {
+	ring := newHashRing(defaultVirtualNodes)
+	clients := []*Client{newTestClient("a:1"), newTestClient("b:1"), newTestClient("c:1")}
+	for _, c := range clients {
+		ring.AddClient(c)
+	}
+
+	victim := clients[0]
+	for _, q := range []string{"q1", "q2", "q3", "q4", "q5"} {
+		if ring.Get(q).Addr() == victim.Addr() {
+			ring.RemoveClient(victim)
+			break
+		}
+	}
+
+	for _, q := range []string{"q1", "q2", "q3", "q4", "q5", "q6", "q7", "q8"} {
+		if got := ring.Get(q); got != nil && got.Addr() == victim.Addr() {
+			t.Fatalf("queue %q still routes to removed client %s", q, victim.Addr())
+		}
+	}
+}
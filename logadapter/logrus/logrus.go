@@ -0,0 +1,19 @@
+// Package logrus adapts a logrus.FieldLogger (satisfied by *logrus.Logger
+// and *logrus.Entry) to the darner.Logger interface.
+package logrus
+
+import "github.com/sirupsen/logrus"
+
+// Logger adapts a logrus.FieldLogger to darner.Logger.
+type Logger struct {
+	l logrus.FieldLogger
+}
+
+// New wraps l so it can be used as a darner.Logger.
+func New(l logrus.FieldLogger) *Logger {
+	return &Logger{l: l}
+}
+
+func (a *Logger) Errorf(format string, args ...interface{}) { a.l.Errorf(format, args...) }
+func (a *Logger) Infof(format string, args ...interface{})  { a.l.Infof(format, args...) }
+func (a *Logger) Debugf(format string, args ...interface{}) { a.l.Debugf(format, args...) }
@@ -0,0 +1,31 @@
+// Package slog adapts a *slog.Logger to the darner.Logger interface.
+// log/slog doesn't have printf-style methods, so each call is formatted
+// before being passed through as the log message.
+package slog
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// Logger adapts a *slog.Logger to darner.Logger.
+type Logger struct {
+	l *slog.Logger
+}
+
+// New wraps l so it can be used as a darner.Logger.
+func New(l *slog.Logger) *Logger {
+	return &Logger{l: l}
+}
+
+func (a *Logger) Errorf(format string, args ...interface{}) {
+	a.l.Error(fmt.Sprintf(format, args...))
+}
+
+func (a *Logger) Infof(format string, args ...interface{}) {
+	a.l.Info(fmt.Sprintf(format, args...))
+}
+
+func (a *Logger) Debugf(format string, args ...interface{}) {
+	a.l.Debug(fmt.Sprintf(format, args...))
+}
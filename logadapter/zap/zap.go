@@ -0,0 +1,18 @@
+// Package zap adapts a *zap.SugaredLogger to the darner.Logger interface.
+package zap
+
+import "go.uber.org/zap"
+
+// Logger adapts a *zap.SugaredLogger to darner.Logger.
+type Logger struct {
+	l *zap.SugaredLogger
+}
+
+// New wraps l so it can be used as a darner.Logger.
+func New(l *zap.SugaredLogger) *Logger {
+	return &Logger{l: l}
+}
+
+func (a *Logger) Errorf(format string, args ...interface{}) { a.l.Errorf(format, args...) }
+func (a *Logger) Infof(format string, args ...interface{})  { a.l.Infof(format, args...) }
+func (a *Logger) Debugf(format string, args ...interface{}) { a.l.Debugf(format, args...) }
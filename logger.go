@@ -0,0 +1,46 @@
+package darner
+
+import "sync"
+
+// Logger is the minimal logging interface ClusterReader needs. Implement
+// it for whatever logging library your application already uses, or use
+// one of the darner/logadapter subpackages (logrus, zap, slog) to wrap a
+// logger you already have.
+type Logger interface {
+	Errorf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Debugf(format string, args ...interface{})
+}
+
+// NopLogger discards everything logged to it. It is the package-level
+// default, so darner works without pulling in any logging dependency.
+type NopLogger struct{}
+
+func (NopLogger) Errorf(format string, args ...interface{}) {}
+func (NopLogger) Infof(format string, args ...interface{})  {}
+func (NopLogger) Debugf(format string, args ...interface{}) {}
+
+var (
+	defaultLoggerMu sync.RWMutex
+	defaultLogger   Logger = NopLogger{}
+)
+
+// SetLogger sets the package-level default Logger used by any
+// ClusterReader that doesn't have its own Logger field set. Safe to call
+// concurrently with running readers.
+func SetLogger(l Logger) {
+	if l == nil {
+		l = NopLogger{}
+	}
+	defaultLoggerMu.Lock()
+	defaultLogger = l
+	defaultLoggerMu.Unlock()
+}
+
+// getDefaultLogger returns the current package-level default Logger. Safe
+// to call concurrently with SetLogger.
+func getDefaultLogger() Logger {
+	defaultLoggerMu.RLock()
+	defer defaultLoggerMu.RUnlock()
+	return defaultLogger
+}
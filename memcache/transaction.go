@@ -0,0 +1,157 @@
+package memcache
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net"
+	"time"
+)
+
+// PinnedConn is a single connection to one Darner server that is held open
+// across multiple commands, rather than being returned to the Client's
+// connection pool after each request. It exists because Darner ties an
+// open queue transaction (a GET issued with the "/open" suffix) to the
+// exact TCP connection that opened it: the transaction can only be
+// resolved by issuing "/close" or "/abort" on that same connection, or by
+// closing the connection, which causes Darner to abort it automatically.
+type PinnedConn struct {
+	addr net.Addr
+	nc   net.Conn
+	rw   *bufio.ReadWriter
+}
+
+// DialPinned opens a new connection to addr that is not part of the
+// Client's own connection pool, so the caller can hold it across several
+// commands, such as a transactional read and the close or abort that
+// resolves it. Callers must call Release once they are done with it to
+// avoid leaking the socket; losing the connection without calling Release
+// still causes Darner to abort any open transaction on its side.
+func (c *Client) DialPinned(addr net.Addr, timeout time.Duration) (*PinnedConn, error) {
+	nc, err := net.DialTimeout(addr.Network(), addr.String(), timeout)
+	if err != nil {
+		return nil, err
+	}
+	return &PinnedConn{
+		addr: addr,
+		nc:   nc,
+		rw:   bufio.NewReadWriter(bufio.NewReader(nc), bufio.NewWriter(nc)),
+	}, nil
+}
+
+// PickServer returns the server address that key hashes to according to
+// the Client's selector, so callers can open a PinnedConn to the right
+// Darner node before issuing a transactional GET.
+func (c *Client) PickServer(key string) (net.Addr, error) {
+	return c.selector.PickServer(key)
+}
+
+// Get issues a GET for key on the pinned connection and returns the item,
+// or ErrCacheMiss if the queue had nothing available.
+func (pc *PinnedConn) Get(key string) (*Item, error) {
+	if _, err := fmt.Fprintf(pc.rw, "get %s\r\n", key); err != nil {
+		return nil, err
+	}
+	if err := pc.rw.Flush(); err != nil {
+		return nil, err
+	}
+
+	line, err := pc.rw.ReadSlice('\n')
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(line, resultEnd) {
+		return nil, ErrCacheMiss
+	}
+
+	var rkey string
+	var flags uint32
+	var size int
+	if _, err := fmt.Sscanf(string(line), "VALUE %s %d %d\r\n", &rkey, &flags, &size); err != nil {
+		return nil, fmt.Errorf("memcache: unexpected line in get response: %q", line)
+	}
+
+	buf := make([]byte, size+2) // value + trailing \r\n
+	if _, err := readFull(pc.rw, buf); err != nil {
+		return nil, err
+	}
+	item := &Item{Key: rkey, Value: buf[:size], Flags: flags}
+
+	if _, err := pc.rw.ReadSlice('\n'); err != nil { // consume END\r\n
+		return nil, err
+	}
+	return item, nil
+}
+
+// Close resolves the transaction that is currently open on this
+// connection for queueName, committing the read.
+func (pc *PinnedConn) Close(queueName string) error {
+	return pc.resolve(queueName, "close")
+}
+
+// Abort resolves the transaction that is currently open on this
+// connection for queueName, returning the item to the head of the queue
+// for redelivery.
+func (pc *PinnedConn) Abort(queueName string) error {
+	return pc.resolve(queueName, "abort")
+}
+
+func (pc *PinnedConn) resolve(queueName, op string) error {
+	if _, err := fmt.Fprintf(pc.rw, "get %s/%s\r\n", queueName, op); err != nil {
+		return err
+	}
+	if err := pc.rw.Flush(); err != nil {
+		return err
+	}
+	_, err := pc.rw.ReadSlice('\n') // END\r\n
+	return err
+}
+
+// Set stores item on the pinned connection.
+func (pc *PinnedConn) Set(item *Item) error {
+	if _, err := fmt.Fprintf(pc.rw, "set %s %d 0 %d\r\n", item.Key, item.Flags, len(item.Value)); err != nil {
+		return err
+	}
+	if _, err := pc.rw.Write(item.Value); err != nil {
+		return err
+	}
+	if _, err := pc.rw.WriteString("\r\n"); err != nil {
+		return err
+	}
+	if err := pc.rw.Flush(); err != nil {
+		return err
+	}
+	line, err := pc.rw.ReadSlice('\n')
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(line, resultStored) {
+		return fmt.Errorf("memcache: unexpected response to set: %q", line)
+	}
+	return nil
+}
+
+// SetDeadline sets the read and write deadline on the underlying
+// connection, so that a caller watching a context.Context can force an
+// in-flight blocking command to return by calling SetDeadline(time.Now()).
+func (pc *PinnedConn) SetDeadline(t time.Time) error {
+	return pc.nc.SetDeadline(t)
+}
+
+// Release closes the underlying TCP connection. If a transaction is still
+// open at this point, Darner aborts it on its side.
+func (pc *PinnedConn) Release() error {
+	return pc.nc.Close()
+}
+
+func readFull(rw *bufio.ReadWriter, buf []byte) (int, error) {
+	n := 0
+	for n < len(buf) {
+		m, err := rw.Read(buf[n:])
+		n += m
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,173 @@
+// Package metrics exposes a prometheus.Collector for darner queues, so
+// that consumers of darner-queue-go don't need to hand-roll their own
+// gauges for queue depth, reader throughput, and backoff behavior.
+package metrics
+
+import (
+	"sync"
+
+	"github.com/apuckey/darner-queue-go"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "darner"
+
+// Collector is a prometheus.Collector wrapping a set of *darner.Clients.
+// On every scrape it polls Client.Stats() for queue depth, and it also
+// exposes counters that ClusterReader and ClusterWriter feed through
+// their OnEvent hook; wire those up with Observe.
+type Collector struct {
+	clients []*darner.Client
+
+	queueItems    *prometheus.GaugeVec
+	queueWaiters  *prometheus.GaugeVec
+	queueOpenTxns *prometheus.GaugeVec
+
+	messagesRead    *prometheus.CounterVec
+	messagesWritten *prometheus.CounterVec
+	cacheMisses     *prometheus.CounterVec
+	errors          *prometheus.CounterVec
+	backoffCurrent  *prometheus.GaugeVec
+	backoffWaitTime *prometheus.CounterVec
+
+	mu sync.Mutex
+}
+
+// New creates a Collector that polls clients for queue stats on every
+// scrape. Use Observe to also feed it reader/writer throughput events.
+func New(clients ...*darner.Client) *Collector {
+	labels := []string{"server", "queue"}
+	return &Collector{
+		clients: clients,
+
+		queueItems: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "queue_items",
+			Help:      "Number of items currently sitting in a queue.",
+		}, labels),
+		queueWaiters: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "queue_waiters",
+			Help:      "Number of clients currently blocked waiting for an item.",
+		}, labels),
+		queueOpenTxns: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "queue_open_transactions",
+			Help:      "Number of reliably-read items awaiting Ack or Nack.",
+		}, labels),
+
+		messagesRead: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "messages_read_total",
+			Help:      "Messages successfully read from a queue.",
+		}, labels),
+		messagesWritten: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "messages_written_total",
+			Help:      "Messages successfully written to a queue.",
+		}, labels),
+		cacheMisses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_misses_total",
+			Help:      "Reads that found no item waiting on the queue.",
+		}, labels),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "errors_total",
+			Help:      "Errors returned by the underlying Darner connection.",
+		}, labels),
+		backoffCurrent: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "backoff_seconds",
+			Help:      "Current backoff sleep duration, 0 when not backing off.",
+		}, labels),
+		backoffWaitTime: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "backoff_wait_seconds_total",
+			Help:      "Cumulative time spent asleep waiting on backoff.",
+		}, labels),
+	}
+}
+
+// Register creates a Collector for clients and registers it with reg.
+func Register(reg prometheus.Registerer, clients ...*darner.Client) (*Collector, error) {
+	c := New(clients...)
+	if err := reg.Register(c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Observe wires r's and w's OnEvent hooks to feed this collector's
+// counters, preserving any hook already set on them. It uses
+// AddOnEvent, so it's safe to call whether or not r or w is already
+// running.
+func (c *Collector) Observe(r *darner.ClusterReader, w *darner.ClusterWriter) {
+	if r != nil {
+		r.AddOnEvent(c.handle)
+	}
+	if w != nil {
+		w.AddOnEvent(c.handle)
+	}
+}
+
+func (c *Collector) handle(e darner.Event) {
+	switch e.Kind {
+	case darner.EventMessageRead:
+		c.messagesRead.WithLabelValues(e.Server, e.Queue).Inc()
+	case darner.EventMessageWritten:
+		c.messagesWritten.WithLabelValues(e.Server, e.Queue).Inc()
+	case darner.EventCacheMiss:
+		c.cacheMisses.WithLabelValues(e.Server, e.Queue).Inc()
+	case darner.EventError:
+		c.errors.WithLabelValues(e.Server, e.Queue).Inc()
+	case darner.EventBackoffStart:
+		c.backoffCurrent.WithLabelValues(e.Server, e.Queue).Set(e.Duration.Seconds())
+	case darner.EventBackoffEnd:
+		c.backoffWaitTime.WithLabelValues(e.Server, e.Queue).Add(e.Duration.Seconds())
+		c.backoffCurrent.WithLabelValues(e.Server, e.Queue).Set(0)
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, v := range c.vecs() {
+		v.Describe(ch)
+	}
+}
+
+// Collect implements prometheus.Collector. It polls every client's Stats
+// for current queue depth before reporting.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, client := range c.clients {
+		servers, err := client.Stats()
+		if err != nil {
+			continue
+		}
+		for addr, server := range servers {
+			if server.ServerErr != nil {
+				continue
+			}
+			for _, q := range server.Queues {
+				c.queueItems.WithLabelValues(addr.String(), q.Name).Set(float64(q.Items))
+				c.queueWaiters.WithLabelValues(addr.String(), q.Name).Set(float64(q.Waiters))
+				c.queueOpenTxns.WithLabelValues(addr.String(), q.Name).Set(float64(q.OpenTransactions))
+			}
+		}
+	}
+
+	for _, v := range c.vecs() {
+		v.Collect(ch)
+	}
+}
+
+func (c *Collector) vecs() []prometheus.Collector {
+	return []prometheus.Collector{
+		c.queueItems, c.queueWaiters, c.queueOpenTxns,
+		c.messagesRead, c.messagesWritten, c.cacheMisses, c.errors,
+		c.backoffCurrent, c.backoffWaitTime,
+	}
+}